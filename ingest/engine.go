@@ -0,0 +1,207 @@
+// Package ingest implements a small single-task crawler: a queue of Requests is
+// drained one at a time, each body is fetched, handed to a ParserFunc, and any
+// follow-up Requests the parser returns are pushed back onto the same queue.
+package ingest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+)
+
+// Request is one item of crawl work: fetch URL, then hand the body to ParserFunc.
+type Request struct {
+	URL        string
+	ParserFunc ParserFunc
+}
+
+// ParserFunc turns a fetched body into zero or more follow-up Requests (e.g. the
+// next page of results, or a newly discovered exam UUID).
+type ParserFunc func(body []byte) ([]Request, error)
+
+// Sink is where fetched bodies end up. FileSink appends them to RawFile.txt so the
+// existing readFile() picks them up on the next restart; MemorySink keeps them in
+// memory so getQuestionCategories can consume them without a restart.
+type Sink interface {
+	Write(body []byte) error
+}
+
+// FileSink appends each body as its own line to Path, matching the newline-delimited
+// JSON format RawFile.txt already uses.
+type FileSink struct {
+	Path string
+}
+
+func (f FileSink) Write(body []byte) error {
+	file, err := os.OpenFile(f.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("ingest: open %s: %w", f.Path, err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(body); err != nil {
+		return fmt.Errorf("ingest: write %s: %w", f.Path, err)
+	}
+	_, err = file.WriteString("\n")
+	return err
+}
+
+// MemorySink keeps every fetched body in memory, for callers (such as
+// getQuestionCategories) that want to serve freshly crawled questions without a
+// restart.
+type MemorySink struct {
+	mu     sync.Mutex
+	bodies [][]byte
+}
+
+func (m *MemorySink) Write(body []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cp := make([]byte, len(body))
+	copy(cp, body)
+	m.bodies = append(m.bodies, cp)
+	return nil
+}
+
+// Bodies returns a snapshot of everything written so far.
+func (m *MemorySink) Bodies() [][]byte {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([][]byte, len(m.bodies))
+	copy(out, m.bodies)
+	return out
+}
+
+// Engine drains a Request queue sequentially: fetch, sink, parse, enqueue follow-ups.
+// It is a single-task crawler, not a worker pool, so RateLimit only has to hold off
+// the next request to the same host rather than coordinate concurrent fetchers.
+type Engine struct {
+	Client     *http.Client
+	Sink       Sink
+	Headers    map[string]string // applied to every outgoing request
+	RateLimit  time.Duration     // minimum gap between requests to the same host
+	MaxRetries int
+	Backoff    time.Duration // base delay, doubled on each retry
+
+	queue     []Request
+	lastFetch map[string]time.Time
+}
+
+// NewEngine returns an Engine with the defaults this crawler has always used:
+// a 3 second per-host rate limit, 3 retries, and a 500ms backoff.
+func NewEngine(client *http.Client, sink Sink) *Engine {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Engine{
+		Client:     client,
+		Sink:       sink,
+		RateLimit:  3 * time.Second,
+		MaxRetries: 3,
+		Backoff:    500 * time.Millisecond,
+		lastFetch:  make(map[string]time.Time),
+	}
+}
+
+// Enqueue adds Requests to the back of the queue.
+func (e *Engine) Enqueue(reqs ...Request) {
+	e.queue = append(e.queue, reqs...)
+}
+
+// Run drains the queue until it is empty, returning the first unrecoverable error.
+// A Request whose fetch exhausts its retries is skipped rather than aborting the
+// whole crawl, since later queue entries are usually independent exams/pages.
+func (e *Engine) Run() error {
+	for len(e.queue) > 0 {
+		req := e.queue[0]
+		e.queue = e.queue[1:]
+
+		body, err := e.fetch(req.URL)
+		if err != nil {
+			fmt.Printf("⚠️  抓取失败，已跳过 %s: %v\n", req.URL, err)
+			continue
+		}
+
+		if e.Sink != nil {
+			if err := e.Sink.Write(body); err != nil {
+				return fmt.Errorf("ingest: sink write for %s: %w", req.URL, err)
+			}
+		}
+
+		if req.ParserFunc != nil {
+			next, err := req.ParserFunc(body)
+			if err != nil {
+				fmt.Printf("⚠️  解析失败，已跳过后续请求 %s: %v\n", req.URL, err)
+				continue
+			}
+			e.Enqueue(next...)
+		}
+	}
+	return nil
+}
+
+func (e *Engine) fetch(rawURL string) ([]byte, error) {
+	e.waitRateLimit(rawURL)
+
+	var lastErr error
+	for attempt := 0; attempt <= e.MaxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(e.Backoff * time.Duration(1<<uint(attempt-1)))
+		}
+
+		req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+		if err != nil {
+			return nil, fmt.Errorf("ingest: build request for %s: %w", rawURL, err)
+		}
+		for k, v := range e.Headers {
+			req.Header.Set(k, v)
+		}
+
+		resp, err := e.Client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if resp.StatusCode >= 500 {
+			lastErr = fmt.Errorf("服务端返回状态码 %d", resp.StatusCode)
+			continue
+		}
+		if resp.StatusCode >= 400 {
+			return nil, fmt.Errorf("服务端返回状态码 %d", resp.StatusCode)
+		}
+
+		return body, nil
+	}
+	return nil, fmt.Errorf("重试 %d 次后仍然失败: %w", e.MaxRetries, lastErr)
+}
+
+// waitRateLimit blocks until RateLimit has elapsed since the last fetch to this
+// request's host.
+func (e *Engine) waitRateLimit(rawURL string) {
+	if e.RateLimit <= 0 {
+		return
+	}
+
+	host := rawURL
+	if u, err := url.Parse(rawURL); err == nil && u.Host != "" {
+		host = u.Host
+	}
+
+	if last, ok := e.lastFetch[host]; ok {
+		if wait := e.RateLimit - time.Since(last); wait > 0 {
+			time.Sleep(wait)
+		}
+	}
+	e.lastFetch[host] = time.Now()
+}
@@ -0,0 +1,85 @@
+package ingest
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// examResponse mirrors just the part of the exam API's JSON that pagination and
+// exam discovery need; the body is still sunk to disk/memory verbatim so
+// readFile's ExamResponse keeps working unchanged.
+type examResponse struct {
+	Data struct {
+		Items map[string]json.RawMessage `json:"items"`
+		Exam  struct {
+			UUID          string `json:"uuid"`
+			QuestionCount int    `json:"question_count"`
+		} `json:"exam"`
+		// RelatedExamUUIDs is the API's "see also" list for this exam (e.g. other
+		// exams in the same category); it drives discovery once the current exam's
+		// pages are exhausted.
+		RelatedExamUUIDs []string `json:"related_exam_uuids"`
+	} `json:"data"`
+}
+
+// ExamAPIParser is the ParserFunc for the exam site's API: given one page of an
+// exam, it works out whether more pages remain, and once they're exhausted,
+// which related exams (from the response's own related_exam_uuids) to crawl next.
+type ExamAPIParser struct {
+	// PageURL builds the URL for the given 1-based page of examUUID.
+	PageURL func(examUUID string, page int) string
+
+	seen    map[string]int  // items seen so far per exam UUID
+	page    map[string]int  // last page requested per exam UUID
+	visited map[string]bool // exam UUIDs already enqueued, so related-exam links can't loop
+}
+
+// Parse implements ParserFunc.
+func (p *ExamAPIParser) Parse(body []byte) ([]Request, error) {
+	var resp examResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("ingest: decode exam response: %w", err)
+	}
+
+	examUUID := resp.Data.Exam.UUID
+	if examUUID == "" {
+		return nil, nil
+	}
+	if p.seen == nil {
+		p.seen = make(map[string]int)
+		p.page = make(map[string]int)
+		p.visited = make(map[string]bool)
+	}
+	p.visited[examUUID] = true
+
+	p.seen[examUUID] += len(resp.Data.Items)
+	p.page[examUUID]++
+
+	if p.PageURL == nil {
+		return nil, nil
+	}
+
+	var next []Request
+
+	if p.seen[examUUID] < resp.Data.Exam.QuestionCount {
+		nextPage := p.page[examUUID] + 1
+		next = append(next, Request{
+			URL:        p.PageURL(examUUID, nextPage),
+			ParserFunc: p.Parse,
+		})
+		return next, nil
+	}
+
+	for _, relatedUUID := range resp.Data.RelatedExamUUIDs {
+		if relatedUUID == "" || p.visited[relatedUUID] {
+			continue
+		}
+		p.visited[relatedUUID] = true
+		next = append(next, Request{
+			URL:        p.PageURL(relatedUUID, 1),
+			ParserFunc: p.Parse,
+		})
+	}
+
+	return next, nil
+}
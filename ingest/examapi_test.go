@@ -0,0 +1,75 @@
+package ingest
+
+import "testing"
+
+func pageURL(examUUID string, page int) string {
+	return examUUID + "?page=" + string(rune('0'+page))
+}
+
+func TestExamAPIParserPaginatesUntilQuestionCountReached(t *testing.T) {
+	p := &ExamAPIParser{PageURL: pageURL}
+
+	page1 := []byte(`{"data":{"items":{"a":1,"b":2},"exam":{"uuid":"exam-1","question_count":3}}}`)
+	next, err := p.Parse(page1)
+	if err != nil {
+		t.Fatalf("Parse(page1) error: %v", err)
+	}
+	if len(next) != 1 || next[0].URL != pageURL("exam-1", 2) {
+		t.Fatalf("Parse(page1) = %+v, want one follow-up request for page 2", next)
+	}
+
+	page2 := []byte(`{"data":{"items":{"c":3},"exam":{"uuid":"exam-1","question_count":3}}}`)
+	next, err = p.Parse(page2)
+	if err != nil {
+		t.Fatalf("Parse(page2) error: %v", err)
+	}
+	if len(next) != 0 {
+		t.Fatalf("Parse(page2) = %+v, want no more pages once question_count is reached", next)
+	}
+}
+
+func TestExamAPIParserFollowsRelatedExamsOnceExhausted(t *testing.T) {
+	p := &ExamAPIParser{PageURL: pageURL}
+
+	resp := []byte(`{"data":{"items":{"a":1},"exam":{"uuid":"exam-1","question_count":1},"related_exam_uuids":["exam-2","exam-3"]}}`)
+	next, err := p.Parse(resp)
+	if err != nil {
+		t.Fatalf("Parse(resp) error: %v", err)
+	}
+	if len(next) != 2 || next[0].URL != pageURL("exam-2", 1) || next[1].URL != pageURL("exam-3", 1) {
+		t.Fatalf("Parse(resp) = %+v, want follow-up requests for exam-2 and exam-3", next)
+	}
+}
+
+func TestExamAPIParserDoesNotRevisitSeenExams(t *testing.T) {
+	p := &ExamAPIParser{PageURL: pageURL}
+
+	resp1 := []byte(`{"data":{"items":{"a":1},"exam":{"uuid":"exam-1","question_count":1},"related_exam_uuids":["exam-2"]}}`)
+	if _, err := p.Parse(resp1); err != nil {
+		t.Fatalf("Parse(resp1) error: %v", err)
+	}
+
+	// exam-2's own response points back at exam-1 (already visited) and itself
+	// (already visited once Parse sees its own uuid) - neither should be re-enqueued.
+	resp2 := []byte(`{"data":{"items":{"b":1},"exam":{"uuid":"exam-2","question_count":1},"related_exam_uuids":["exam-1","exam-2"]}}`)
+	next, err := p.Parse(resp2)
+	if err != nil {
+		t.Fatalf("Parse(resp2) error: %v", err)
+	}
+	if len(next) != 0 {
+		t.Fatalf("Parse(resp2) = %+v, want no follow-ups for already-visited exams", next)
+	}
+}
+
+func TestExamAPIParserStopsWithoutPageURL(t *testing.T) {
+	p := &ExamAPIParser{}
+
+	resp := []byte(`{"data":{"items":{"a":1},"exam":{"uuid":"exam-1","question_count":5},"related_exam_uuids":["exam-2"]}}`)
+	next, err := p.Parse(resp)
+	if err != nil {
+		t.Fatalf("Parse(resp) error: %v", err)
+	}
+	if len(next) != 0 {
+		t.Fatalf("Parse(resp) = %+v, want no follow-ups when PageURL is nil", next)
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"encoding/json"
+	"flag"
 	"fmt"
 	"html/template"
 	"io/ioutil"
@@ -11,7 +12,15 @@ import (
 	"sort"
 	"strconv"
 	"strings"
-	"unicode"
+	"sync"
+	"time"
+
+	"questionSearch1/ingest"
+	"questionSearch1/search"
+	"questionSearch1/semantic"
+	"questionSearch1/session"
+
+	"github.com/xuri/excelize/v2"
 )
 
 // 主响应结构体
@@ -121,6 +130,36 @@ func getDifficultyName(difficulty string) string {
 	}
 }
 
+// questionTypeFromSheetName is the reverse of getQuestionTypeName, used when
+// importing an XLSX workbook back into question types.
+func questionTypeFromSheetName(name string) string {
+	switch name {
+	case "单选题":
+		return "single_choice"
+	case "多选题":
+		return "choice"
+	case "判断题":
+		return "determine"
+	default:
+		return ""
+	}
+}
+
+// difficultyCodeFromName is the reverse of getDifficultyName.
+func difficultyCodeFromName(name string) string {
+	switch name {
+	case "简单":
+		return "simple"
+	case "中等":
+		return "normal"
+	case "困难":
+		return "difficulty"
+	case "较难":
+		return "quite_difficulty"
+	default:
+		return name
+	}
+}
 
 // 辅助函数：安全获取分数值
 func getScore(score interface{}) string {
@@ -165,9 +204,27 @@ func Handler(w http.ResponseWriter, r *http.Request) {
 }
 
 func main() {
+	mode := flag.String("mode", "serve", "运行模式: crawl(只抓取题库)|serve(只提供搜索服务)|both(先抓取再提供服务)")
+	flag.Parse()
+
+	if *mode == "crawl" || *mode == "both" {
+		if err := runCrawl(); err != nil {
+			fmt.Printf("❌ 抓取题库失败: %v\n", err)
+		}
+	}
+	if *mode == "crawl" {
+		return
+	}
+
 	// 设置路由
 	http.HandleFunc("/", handleIndex)
 	http.HandleFunc("/search", handleSearch)
+	http.HandleFunc("/exam/start", handleExamStart)
+	http.HandleFunc("/exam/{sessionID}/question/{n}", handleExamQuestion)
+	http.HandleFunc("/exam/{sessionID}/submit", handleExamSubmit)
+	http.HandleFunc("/exam/{sessionID}/result", handleExamResult)
+	http.HandleFunc("/export", handleExport)
+	http.HandleFunc("/import", handleImport)
 
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -184,9 +241,56 @@ func main() {
 
 }
 
+// crawledQuestions holds every response body fetched by the crawler when it runs
+// memory-only (EXAM_CRAWL_SINK=memory), so the questions it found are servable
+// immediately without a disk write. readFile merges its contents in on every
+// call; in the default file-backed mode it stays empty, so the merge is a no-op.
+var crawledQuestions = &ingest.MemorySink{}
+
+// runCrawl refreshes the question corpus from the exam site's API instead of a
+// manual file drop. EXAM_API_BASE and EXAM_SEED_UUID configure which exam to
+// start from; once that exam's pages are exhausted, ExamAPIParser keeps going
+// on its own by following the related_exam_uuids each response carries, so the
+// crawl isn't limited to the one seed exam.
+//
+// By default fetched bodies are appended to RawFile.txt, same as a manual file
+// drop. Setting EXAM_CRAWL_SINK=memory instead sinks them to crawledQuestions,
+// for deployments (e.g. a read-only serverless filesystem) where RawFile.txt
+// can't be written — -mode=both can still serve what it just crawled.
+func runCrawl() error {
+	apiBase := os.Getenv("EXAM_API_BASE")
+	if apiBase == "" {
+		return fmt.Errorf("未设置 EXAM_API_BASE，无法抓取题库")
+	}
+	seedUUID := os.Getenv("EXAM_SEED_UUID")
+	if seedUUID == "" {
+		return fmt.Errorf("未设置 EXAM_SEED_UUID，无法抓取题库")
+	}
+
+	var sink ingest.Sink = ingest.FileSink{Path: "./RawFile.txt"}
+	if os.Getenv("EXAM_CRAWL_SINK") == "memory" {
+		sink = crawledQuestions
+	}
+	engine := ingest.NewEngine(http.DefaultClient, sink)
+	parser := &ingest.ExamAPIParser{
+		PageURL: func(examUUID string, page int) string {
+			return fmt.Sprintf("%s/exam/%s?page=%d", apiBase, examUUID, page)
+		},
+	}
+
+	fmt.Printf("🕷️  开始抓取题库，起始试卷: %s\n", seedUUID)
+	engine.Enqueue(ingest.Request{URL: parser.PageURL(seedUUID, 1), ParserFunc: parser.Parse})
+	if err := engine.Run(); err != nil {
+		return err
+	}
+	fmt.Printf("✅ 题库抓取完成\n")
+	return nil
+}
+
 type SearchConfig struct {
-	Keyword string
-	Type    string
+	Keyword    string
+	Type       string
+	Difficulty string // optional: "simple"/"normal"/"difficulty"/"quite_difficulty"
 }
 
 func handleSearch(w http.ResponseWriter, r *http.Request) {
@@ -261,6 +365,7 @@ func handleSearch(w http.ResponseWriter, r *http.Request) {
 }
 
 type Result struct {
+	UUID      string
 	Title 	  string
 	Info   	  string
 	Operator  []string
@@ -268,6 +373,80 @@ type Result struct {
 	Type      string
 }
 
+var (
+	searchIndexMu sync.Mutex
+	searchIndex   *search.Index
+)
+
+// getSearchIndex lazily builds the BM25 index over every question's cleaned title
+// and choices, the first time a search is performed, and reuses it afterwards.
+// invalidateSearchIndex forces the next call to rebuild it, so newly imported
+// questions become searchable without a process restart.
+func getSearchIndex() *search.Index {
+	searchIndexMu.Lock()
+	defer searchIndexMu.Unlock()
+	if searchIndex != nil {
+		return searchIndex
+	}
+
+	idx := search.NewIndex()
+	_, allQuestions := readFile()
+	for uuid, question := range allQuestions {
+		var text strings.Builder
+		text.WriteString(cleanText(question.Title))
+		for _, choice := range question.Choices {
+			text.WriteString(" ")
+			text.WriteString(cleanText(choice.Title))
+		}
+		idx.Add(uuid, text.String())
+	}
+	searchIndex = idx
+	return searchIndex
+}
+
+// invalidateSearchIndex drops the cached BM25 index so getSearchIndex rebuilds
+// it from RawFile.txt on the next call.
+func invalidateSearchIndex() {
+	searchIndexMu.Lock()
+	searchIndex = nil
+	searchIndexMu.Unlock()
+}
+
+var (
+	relevanceRankerOnce sync.Once
+	relevanceRanker     semantic.Ranker
+	relevanceAlpha      = 0.5 // weight given to the semantic score when blending with BM25
+)
+
+// getRelevanceRanker lazily builds the semantic ranking backend: remote if
+// SEMANTIC_API_URL is configured (cached on disk so the endpoint isn't called
+// twice for the same query/question pair), local hashed-ngram cosine otherwise.
+// SEMANTIC_ALPHA (default 0.5) controls how much weight its score gets against
+// the BM25 lexical score.
+func getRelevanceRanker() semantic.Ranker {
+	relevanceRankerOnce.Do(func() {
+		if raw := os.Getenv("SEMANTIC_ALPHA"); raw != "" {
+			if v, err := strconv.ParseFloat(raw, 64); err == nil {
+				relevanceAlpha = v
+			}
+		}
+
+		if remote, ok := semantic.NewRemoteRankerFromEnv(); ok {
+			cache, err := semantic.NewCache("./semantic_cache.json", 10000)
+			if err != nil {
+				fmt.Printf("⚠️ 语义相关性缓存初始化失败，已降级为本地相似度: %v\n", err)
+				relevanceRanker = semantic.NewLocalRanker()
+				return
+			}
+			relevanceRanker = &semantic.CachingRanker{Inner: remote, Cache: cache}
+			return
+		}
+
+		relevanceRanker = semantic.NewLocalRanker()
+	})
+	return relevanceRanker
+}
+
 func searchQuestions(config SearchConfig) ([]Result, error) {
 	var allQuestions []Result
 	var category *QuestionCategory
@@ -290,8 +469,13 @@ func searchQuestions(config SearchConfig) ([]Result, error) {
 		return nil, fmt.Errorf("无效的题型选择")
 	}
 
+	if category == nil {
+		return nil, nil
+	}
+
 	for _, question := range category.Questions {
 		var res Result
+		res.UUID = question.UUID
 		res.Title = fmt.Sprintf(" %s\n", cleanText(question.Title))
 		res.Info =  fmt.Sprintf(" 难度: %s | 分值: %s | 正确答案: %s\n", getDifficultyName(question.Difficulty), getScore(question.Score), question.ShowAnswer)
 		res.Type = getQuestionTypeName(config.Type)
@@ -309,9 +493,33 @@ func searchQuestions(config SearchConfig) ([]Result, error) {
 		allQuestions = append(allQuestions, res)
 	}
 
-	// 计算相关性分数
-	for i := range allQuestions {
-		allQuestions[i].Relevance = calculateRelevance(allQuestions[i].Title, config.Keyword)
+	// 计算相关性分数：BM25词法分数与语义相似度分数按 relevanceAlpha 加权融合，
+	// 这样换一种问法提问也能命中话题相关的题目，而不只是字面匹配。
+	if config.Keyword == "" {
+		for i := range allQuestions {
+			allQuestions[i].Relevance = 1.0 // 如果没有关键词，所有问题都显示
+		}
+	} else {
+		idx := getSearchIndex()
+		ranker := getRelevanceRanker()
+
+		// 先对整个索引做一次BM25全量扫描，再按UUID查表，避免下面每道题都调用
+		// idx.Score（每次都会重新扫描整个索引）导致 O(n·词项命中数) 次全量扫描。
+		lexicalScores := make(map[string]float64, len(allQuestions))
+		for _, scored := range idx.Search(config.Keyword, -1) {
+			lexicalScores[scored.UUID] = scored.Score
+		}
+
+		docs := make([]string, len(allQuestions))
+		for i := range allQuestions {
+			docs[i] = allQuestions[i].Title
+		}
+		semanticScores := ranker.Score(config.Keyword, docs)
+
+		for i := range allQuestions {
+			lexical := lexicalScores[allQuestions[i].UUID]
+			allQuestions[i].Relevance = (1-relevanceAlpha)*lexical + relevanceAlpha*semanticScores[i]
+		}
 	}
 
 	// 按相关性从高到低排序
@@ -327,7 +535,7 @@ func searchQuestions(config SearchConfig) ([]Result, error) {
 	if config.Keyword != "" {
 		var filtered []Result
 		for _, q := range allQuestions {
-			if q.Relevance > 0.5 { // 设置阈值，只返回相关性较高的结果
+			if q.Relevance > 0 { // 融合分数不再是0-1区间，只要BM25命中任意词项或语义分数为正就保留
 				filtered = append(filtered, q)
 			}
 		}
@@ -343,75 +551,566 @@ func searchQuestions(config SearchConfig) ([]Result, error) {
 	return allQuestions, nil
 }
 
-func calculateRelevance(question, keyword string) float64 {
-	if keyword == "" {
-		return 1.0 // 如果没有关键词，所有问题都显示
+// examManager tracks in-progress "take exam" attempts; an in-memory store today,
+// swappable for a Redis/SQLite-backed session.Store later.
+var examManager = session.NewManager(session.NewMemoryStore())
+
+// selectExamQuestions filters the question bank by type, then by difficulty if one
+// is given, then by BM25 relevance alone if a keyword is given (unlike
+// searchQuestions, no semantic score is blended in), and returns the raw
+// questions so a session can grade them, rather than the pre-rendered Result text
+// searchQuestions produces.
+func selectExamQuestions(config SearchConfig) ([]session.Question, error) {
+	questionCategories := getQuestionCategories()
+
+	var category *QuestionCategory
+	switch config.Type {
+	case "single_choice":
+		category = questionCategories["单选题"]
+	case "choice":
+		category = questionCategories["多选题"]
+	case "determine":
+		category = questionCategories["判断题"]
+	default:
+		return nil, fmt.Errorf("无效的题型选择")
+	}
+	if category == nil {
+		return nil, nil
+	}
+
+	items := category.Questions
+	if config.Difficulty != "" {
+		filtered := make([]QuestionItem, 0, len(items))
+		for _, item := range items {
+			if item.Difficulty == config.Difficulty {
+				filtered = append(filtered, item)
+			}
+		}
+		items = filtered
+	}
+	if config.Keyword != "" {
+		idx := getSearchIndex()
+
+		// 先为每道题计算一次BM25分数，避免 sort.Slice 的比较函数重复调用
+		// idx.Score（每次都会重新扫描整个索引），导致 O(n log n) 次全量扫描。
+		scores := make(map[string]float64, len(items))
+		for _, item := range items {
+			scores[item.UUID] = idx.Score(item.UUID, config.Keyword)
+		}
+
+		filtered := make([]QuestionItem, 0, len(items))
+		for _, item := range items {
+			if scores[item.UUID] > 0 {
+				filtered = append(filtered, item)
+			}
+		}
+		sort.Slice(filtered, func(i, j int) bool {
+			return scores[filtered[i].UUID] > scores[filtered[j].UUID]
+		})
+		if len(filtered) > 20 {
+			filtered = filtered[:20]
+		}
+		items = filtered
+	}
+
+	questions := make([]session.Question, 0, len(items))
+	for _, item := range items {
+		score, _ := strconv.ParseFloat(getScore(item.Score), 64)
+		q := session.Question{
+			UUID:  item.UUID,
+			Type:  item.Type,
+			Title: cleanText(item.Title),
+			Score: score,
+		}
+		for _, choice := range item.Choices {
+			q.Choices = append(q.Choices, session.Choice{
+				Operator:  choice.Operator,
+				IsCorrect: choice.IsCorrect,
+			})
+		}
+		questions = append(questions, q)
+	}
+	return questions, nil
+}
+
+// examQuestionData feeds the "./exam_question.html" template.
+type examQuestionData struct {
+	SessionID string
+	Index     int // 1-based
+	Total     int
+	Question  session.Question
+	Selected  []string
+}
+
+// handleExamStart filters the question bank (same query params as /search —
+// keyword, question_type — plus an optional difficulty) into an exam, opens a
+// session for it, and sends the user to the first question.
+func handleExamStart(w http.ResponseWriter, r *http.Request) {
+	config := SearchConfig{
+		Keyword:    strings.TrimSpace(r.FormValue("keyword")),
+		Type:       r.FormValue("question_type"),
+		Difficulty: r.FormValue("difficulty"),
+	}
+
+	questions, err := selectExamQuestions(config)
+	if err != nil {
+		http.Error(w, "筛选题目错误: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(questions) == 0 {
+		http.Error(w, "没有找到符合条件的题目", http.StatusNotFound)
+		return
+	}
+
+	sess, err := examManager.Start(w, r, questions, defaultExamInfo.MaxDuration)
+	if err != nil {
+		http.Error(w, "创建考试会话失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/exam/%s/question/1", sess.ID), http.StatusSeeOther)
+}
+
+// defaultExamInfo.MaxDuration bounds how long a session stays open; there is no
+// per-exam ExamInfo once questions are merged into one pool, so every "take exam"
+// attempt shares this limit.
+var defaultExamInfo = ExamInfo{MaxDuration: 3600}
+
+// handleExamQuestion renders question n of a session on GET, or records the
+// posted answer and advances to the next question (or to /submit, on the last
+// question) on POST.
+func handleExamQuestion(w http.ResponseWriter, r *http.Request) {
+	sessionID := r.PathValue("sessionID")
+	n, err := strconv.Atoi(r.PathValue("n"))
+	if err != nil || n < 1 {
+		http.Error(w, "无效的题号", http.StatusBadRequest)
+		return
+	}
+
+	sess, err := examManager.Store.Get(sessionID)
+	if err != nil {
+		http.Error(w, "考试会话不存在或已过期", http.StatusNotFound)
+		return
+	}
+	if n > len(sess.Questions) {
+		http.Error(w, "无效的题号", http.StatusBadRequest)
+		return
+	}
+
+	// 超时后不再接受新答案，直接收卷按已作答内容计分。
+	if sess.Expired() {
+		http.Redirect(w, r, fmt.Sprintf("/exam/%s/submit", sessionID), http.StatusSeeOther)
+		return
+	}
+
+	question := sess.Questions[n-1]
+
+	if r.Method == http.MethodPost {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "表单解析错误: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		sess.Answer(question.UUID, r.Form["operator"])
+
+		if n == len(sess.Questions) {
+			http.Redirect(w, r, fmt.Sprintf("/exam/%s/submit", sessionID), http.StatusSeeOther)
+			return
+		}
+		http.Redirect(w, r, fmt.Sprintf("/exam/%s/question/%d", sessionID, n+1), http.StatusSeeOther)
+		return
+	}
+
+	tmpl, err := template.ParseFiles("./exam_question.html")
+	if err != nil {
+		http.Error(w, "模板解析错误: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	data := examQuestionData{
+		SessionID: sessionID,
+		Index:     n,
+		Total:     len(sess.Questions),
+		Question:  question,
+		Selected:  sess.SelectedFor(question.UUID),
+	}
+	if err := tmpl.Execute(w, data); err != nil {
+		http.Error(w, "模板渲染错误: "+err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// handleExamSubmit grades every question in the session and sends the user to
+// the result page.
+func handleExamSubmit(w http.ResponseWriter, r *http.Request) {
+	sessionID := r.PathValue("sessionID")
+	sess, err := examManager.Store.Get(sessionID)
+	if err != nil {
+		http.Error(w, "考试会话不存在或已过期", http.StatusNotFound)
+		return
+	}
+
+	sess.Submit()
+	http.Redirect(w, r, fmt.Sprintf("/exam/%s/result", sessionID), http.StatusSeeOther)
+}
+
+// examResultData feeds the "./exam_result.html" template.
+type examResultData struct {
+	SessionID  string
+	TotalScore float64
+	CorrectNum int
+	TotalNum   int
+	Elapsed    string
+	Results    []session.Result
+}
+
+// handleExamResult renders the scored summary for a submitted session.
+func handleExamResult(w http.ResponseWriter, r *http.Request) {
+	sessionID := r.PathValue("sessionID")
+	sess, err := examManager.Store.Get(sessionID)
+	if err != nil {
+		http.Error(w, "考试会话不存在或已过期", http.StatusNotFound)
+		return
+	}
+	if sess.SubmittedAt.IsZero() {
+		sess.Submit()
+	}
+
+	correct := 0
+	for _, res := range sess.Results {
+		if res.Correct {
+			correct++
+		}
 	}
 
-	questionLower := strings.ToLower(question)
-	keywordLower := strings.ToLower(keyword)
+	tmpl, err := template.ParseFiles("./exam_result.html")
+	if err != nil {
+		http.Error(w, "模板解析错误: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	data := examResultData{
+		SessionID:  sessionID,
+		TotalScore: sess.TotalScore(),
+		CorrectNum: correct,
+		TotalNum:   len(sess.Results),
+		Elapsed:    sess.SubmittedAt.Sub(sess.StartedAt).Round(time.Second).String(),
+		Results:    sess.Results,
+	}
+	if err := tmpl.Execute(w, data); err != nil {
+		http.Error(w, "模板渲染错误: "+err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// exportSheet is one worksheet's worth of questions, named after the Chinese
+// question-type name (so it round-trips through handleImport).
+type exportSheet struct {
+	Name  string
+	Items []QuestionItem
+}
+
+// handleExport streams an XLSX workbook. A GET exports the whole bank (optionally
+// filtered to one ?type=), one sheet per question type; a POST exports exactly the
+// UUIDs the results template posts back ("Export current results").
+//
+// NOTE: root.html is not part of this tree (no .html files are tracked here), so
+// there is no template to add the "Export current results" button to. This POST
+// path is ready for it — it takes a "uuids" + "question_type" form, same shape
+// currentResultsSheet expects below — but wiring the actual button is out of
+// scope until a results template exists to wire it into.
+func handleExport(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "xlsx"
+	}
+	if format != "xlsx" {
+		http.Error(w, "暂不支持的导出格式: "+format, http.StatusBadRequest)
+		return
+	}
+
+	var sheets []exportSheet
+	if r.Method == http.MethodPost {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "表单解析错误: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		sheets = []exportSheet{currentResultsSheet(r)}
+	} else {
+		sheets = allQuestionSheets(r.URL.Query().Get("type"))
+	}
+
+	f, err := buildExportWorkbook(sheets)
+	if err != nil {
+		http.Error(w, "导出错误: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer f.Close()
+
+	w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	w.Header().Set("Content-Disposition", `attachment; filename="questions.xlsx"`)
+	if err := f.Write(w); err != nil {
+		http.Error(w, "导出错误: "+err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// allQuestionSheets returns one sheet per question type, optionally narrowed to a
+// single type code ("single_choice"/"choice"/"determine"); empty or "all" exports
+// every type.
+func allQuestionSheets(onlyType string) []exportSheet {
+	categories := getQuestionCategories()
+	types := []string{"single_choice", "choice", "determine"}
+
+	var sheets []exportSheet
+	for _, qType := range types {
+		if onlyType != "" && onlyType != "all" && onlyType != qType {
+			continue
+		}
+		name := getQuestionTypeName(qType)
+		if category := categories[name]; category != nil {
+			sheets = append(sheets, exportSheet{Name: name, Items: category.Questions})
+		}
+	}
+	return sheets
+}
+
+// currentResultsSheet rebuilds the question set behind a posted "uuids" +
+// "question_type" form, so the results template can download exactly what it's
+// showing without re-running the search.
+func currentResultsSheet(r *http.Request) exportSheet {
+	name := getQuestionTypeName(r.FormValue("question_type"))
+
+	wanted := make(map[string]bool)
+	for _, uuid := range strings.Split(r.FormValue("uuids"), ",") {
+		if uuid = strings.TrimSpace(uuid); uuid != "" {
+			wanted[uuid] = true
+		}
+	}
+
+	var items []QuestionItem
+	if category := getQuestionCategories()[name]; category != nil {
+		for _, item := range category.Questions {
+			if wanted[item.UUID] {
+				items = append(items, item)
+			}
+		}
+	}
+	return exportSheet{Name: name, Items: items}
+}
+
+// buildExportWorkbook renders each non-empty sheet; excelize.NewFile always starts
+// with one default "Sheet1", so the first sheet we write reuses and renames it.
+func buildExportWorkbook(sheets []exportSheet) (*excelize.File, error) {
+	f := excelize.NewFile()
+
+	wrote := false
+	for _, sheet := range sheets {
+		if len(sheet.Items) == 0 {
+			continue
+		}
+		if !wrote {
+			if err := f.SetSheetName("Sheet1", sheet.Name); err != nil {
+				return nil, err
+			}
+		} else if _, err := f.NewSheet(sheet.Name); err != nil {
+			return nil, err
+		}
+		if err := writeQuestionSheet(f, sheet.Name, sheet.Items); err != nil {
+			return nil, err
+		}
+		wrote = true
+	}
+	if !wrote {
+		return nil, fmt.Errorf("没有可导出的题目")
+	}
+
+	f.SetActiveSheet(0)
+	return f, nil
+}
+
+// writeQuestionSheet writes the header row (UUID, 题目, 选项A..选项?, 正确答案, 难度,
+// 分值) and one data row per item. The option column count is sized to the widest
+// question on this sheet.
+func writeQuestionSheet(f *excelize.File, sheet string, items []QuestionItem) error {
+	maxChoices := 0
+	for _, item := range items {
+		if len(item.Choices) > maxChoices {
+			maxChoices = len(item.Choices)
+		}
+	}
 
-	// 1. 完全匹配最高分
-	if questionLower == keywordLower {
-		return 1.0
+	header := []string{"UUID", "题目"}
+	for i := 0; i < maxChoices; i++ {
+		header = append(header, fmt.Sprintf("选项%c", 'A'+i))
 	}
+	header = append(header, "正确答案", "难度", "分值")
 
-	// 2. 开头匹配高分
-	if strings.HasPrefix(questionLower, keywordLower) {
-		return 0.95
+	if err := writeRow(f, sheet, 1, toInterfaceRow(header)); err != nil {
+		return err
 	}
 
-	// 3. 包含完整关键词
-	if strings.Contains(questionLower, keywordLower) {
-		// 检查是否在单词边界
-		index := strings.Index(questionLower, keywordLower)
-		if index > 0 {
-			prevChar := rune(questionLower[index-1])
-			if unicode.IsSpace(prevChar) || unicode.IsPunct(prevChar) {
-				return 0.9
+	for i, item := range items {
+		row := []interface{}{item.UUID, cleanText(item.Title)}
+		for c := 0; c < maxChoices; c++ {
+			if c < len(item.Choices) {
+				row = append(row, cleanText(item.Choices[c].Title))
+			} else {
+				row = append(row, "")
 			}
 		}
-		return 0.8
+		row = append(row, item.ShowAnswer, getDifficultyName(item.Difficulty), getScore(item.Score))
+
+		if err := writeRow(f, sheet, i+2, row); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	// 4. 分词匹配
-	keywordWords := strings.Fields(keywordLower)
-	questionWords := strings.Fields(questionLower)
+func writeRow(f *excelize.File, sheet string, row int, values []interface{}) error {
+	for col, v := range values {
+		cell, err := excelize.CoordinatesToCellName(col+1, row)
+		if err != nil {
+			return err
+		}
+		if err := f.SetCellValue(sheet, cell, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	if len(keywordWords) == 0 {
-		return 0
+func toInterfaceRow(values []string) []interface{} {
+	out := make([]interface{}, len(values))
+	for i, v := range values {
+		out[i] = v
 	}
+	return out
+}
 
-	// 计算匹配的单词数量和位置
-	matchedWords := 0
-	exactWordMatches := 0
+// handleImport reads an uploaded XLSX in the shape handleExport produces, skips
+// UUIDs already present in RawFile.txt (same dedup rule as readFile), and appends
+// the rest as one synthesized ExamResponse JSON line so they survive a restart.
+func handleImport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "仅支持POST请求", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		http.Error(w, "表单解析错误: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "未找到上传文件: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
 
-	for _, kw := range keywordWords {
-		found := false
-		for _, qw := range questionWords {
-			if qw == kw {
-				exactWordMatches++
-				found = true
-				break
-			} else if strings.Contains(qw, kw) {
-				matchedWords++
-				found = true
-				break
+	workbook, err := excelize.OpenReader(file)
+	if err != nil {
+		http.Error(w, "解析XLSX失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer workbook.Close()
+
+	_, existing := readFile()
+	newItems := make(map[string]QuestionItem)
+	skipped := 0
+
+	for _, sheetName := range workbook.GetSheetList() {
+		qType := questionTypeFromSheetName(sheetName)
+		if qType == "" {
+			continue // 不是题库工作表，跳过
+		}
+
+		rows, err := workbook.GetRows(sheetName)
+		if err != nil {
+			http.Error(w, "读取工作表失败: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if len(rows) == 0 {
+			continue
+		}
+
+		header := make(map[string]int, len(rows[0]))
+		for i, name := range rows[0] {
+			header[name] = i
+		}
+
+		for _, row := range rows[1:] {
+			item, uuid, ok := parseQuestionRow(row, header, qType)
+			if !ok {
+				continue
 			}
+			if _, exists := existing[uuid]; exists {
+				skipped++
+				continue
+			}
+			newItems[uuid] = item
 		}
-		if !found {
-			// 如果有任何一个关键词完全没匹配，相关性降低
-			return 0.3
+	}
+
+	if len(newItems) == 0 {
+		fmt.Fprintf(w, "⚠️ 没有新题目可导入，跳过 %d 条重复题目", skipped)
+		return
+	}
+
+	body, err := json.Marshal(ExamResponse{
+		Code:    "0",
+		Status:  "success",
+		Message: "导入自XLSX",
+		Data:    Data{Items: newItems},
+	})
+	if err != nil {
+		http.Error(w, "序列化题库失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if err := (ingest.FileSink{Path: "./RawFile.txt"}).Write(body); err != nil {
+		http.Error(w, "写入题库失败: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	invalidateSearchIndex()
+
+	fmt.Fprintf(w, "✅ 导入成功：新增 %d 条题目，跳过 %d 条重复题目", len(newItems), skipped)
+}
+
+// parseQuestionRow turns one data row back into a QuestionItem, using header to
+// look up columns by name (robust to option-column count varying by sheet).
+func parseQuestionRow(row []string, header map[string]int, qType string) (item QuestionItem, uuid string, ok bool) {
+	get := func(col string) string {
+		idx, exists := header[col]
+		if !exists || idx >= len(row) {
+			return ""
 		}
+		return row[idx]
+	}
+
+	uuid = get("UUID")
+	if uuid == "" {
+		return QuestionItem{}, "", false
 	}
 
-	// 计算基础分数
-	baseScore := float64(matchedWords+exactWordMatches) / float64(len(keywordWords))
+	var choices []Choice
+	for col, idx := range header {
+		if !strings.HasPrefix(col, "选项") || idx >= len(row) || row[idx] == "" {
+			continue
+		}
+		choices = append(choices, Choice{Operator: strings.TrimPrefix(col, "选项"), Title: row[idx]})
+	}
+	sort.Slice(choices, func(i, j int) bool { return choices[i].Operator < choices[j].Operator })
 
-	// 精确单词匹配加分
-	exactBonus := float64(exactWordMatches) * 0.1
+	mask := get("正确答案")
+	for i := range choices {
+		choices[i].IsCorrect = strings.Contains(mask, choices[i].Operator)
+	}
 
-	return baseScore*0.7 + exactBonus
+	item = QuestionItem{
+		UUID:       uuid,
+		Type:       qType,
+		Title:      get("题目"),
+		Score:      get("分值"),
+		ShowAnswer: mask,
+		Choices:    choices,
+		Difficulty: difficultyCodeFromName(get("难度")),
+	}
+	return item, uuid, true
 }
 
 // 处理首页
@@ -458,10 +1157,15 @@ func getQuestionCategories()  map[string]*QuestionCategory {
 }
 
 func readFile() (error, map[string]QuestionItem) {
-	// 读取文件
+	// 读取文件；在 EXAM_CRAWL_SINK=memory 等只读文件系统部署下 RawFile.txt
+	// 可能根本不存在，此时视为空题库，而不是让服务在第一个请求上就崩溃
 	content, err := ioutil.ReadFile("./RawFile.txt")
 	if err != nil {
-		log.Fatal("读取文件失败:", err)
+		if os.IsNotExist(err) {
+			content = nil
+		} else {
+			log.Fatal("读取文件失败:", err)
+		}
 	}
 
 	// 按行分割内容
@@ -493,6 +1197,20 @@ func readFile() (error, map[string]QuestionItem) {
 		}
 		fmt.Printf("\n=== 第%d条数据遍历完成, 总题目数: %d, 新增题数:%d ===\n", i+1, len(examResponse.Data.Items), newQuestion)
 	}
+
+	// 合并爬虫以 EXAM_CRAWL_SINK=memory 模式本次运行期间抓取到的题目（这些题目不写盘，不重启进程也能生效）
+	for _, body := range crawledQuestions.Bodies() {
+		var examResponse ExamResponse
+		if err := json.Unmarshal(body, &examResponse); err != nil {
+			continue
+		}
+		for uuid, item := range examResponse.Data.Items {
+			if _, exists := allQuestions[uuid]; !exists {
+				allQuestions[uuid] = item
+			}
+		}
+	}
+
 	return err, allQuestions
 }
 
@@ -0,0 +1,139 @@
+// Package search provides a small in-memory inverted index ranked with Okapi
+// BM25, used in place of the old "does the title contain the keyword" check.
+package search
+
+import (
+	"math"
+	"sort"
+	"sync"
+)
+
+// ScoredDoc is one ranked hit: a document id (the question UUID) and its BM25 score.
+type ScoredDoc struct {
+	UUID  string
+	Score float64
+}
+
+// Index is an inverted index over a set of documents, keyed by an arbitrary id
+// (the question UUID). It is safe for concurrent use.
+type Index struct {
+	K1 float64
+	B  float64
+
+	mu       sync.RWMutex
+	postings map[string]map[string]int // term -> docID -> term frequency
+	docLen   map[string]int            // docID -> token count
+	totalLen int
+}
+
+// NewIndex returns an empty Index with the standard BM25 defaults (k1=1.5, b=0.75).
+func NewIndex() *Index {
+	return &Index{
+		K1:       1.5,
+		B:        0.75,
+		postings: make(map[string]map[string]int),
+		docLen:   make(map[string]int),
+	}
+}
+
+// Add indexes text under docID. A docID that has already been added is left
+// untouched, mirroring readFile's skip-duplicate behaviour.
+func (idx *Index) Add(docID, text string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if _, exists := idx.docLen[docID]; exists {
+		return
+	}
+
+	tf := make(map[string]int)
+	for _, t := range Tokenize(text) {
+		tf[t]++
+	}
+
+	for t, c := range tf {
+		if idx.postings[t] == nil {
+			idx.postings[t] = make(map[string]int)
+		}
+		idx.postings[t][docID] = c
+	}
+
+	length := 0
+	for _, c := range tf {
+		length += c
+	}
+	idx.docLen[docID] = length
+	idx.totalLen += length
+}
+
+func (idx *Index) avgDocLen() float64 {
+	if len(idx.docLen) == 0 {
+		return 0
+	}
+	return float64(idx.totalLen) / float64(len(idx.docLen))
+}
+
+// idf is the BM25 inverse document frequency for a term with document frequency df,
+// over a corpus of n documents.
+func idf(n, df float64) float64 {
+	return math.Log((n-df+0.5)/(df+0.5) + 1)
+}
+
+// Search tokenizes query and ranks every document that shares at least one term
+// with it, returning the topK highest-scoring documents (topK<=0 means "all").
+// Repeating a term in the query (e.g. "安全 安全") boosts its contribution, since
+// each occurrence is scored separately.
+func (idx *Index) Search(query string, topK int) []ScoredDoc {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	scores := idx.scoreAll(Tokenize(query))
+	results := make([]ScoredDoc, 0, len(scores))
+	for docID, score := range scores {
+		results = append(results, ScoredDoc{UUID: docID, Score: score})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score == results[j].Score {
+			return results[i].UUID < results[j].UUID
+		}
+		return results[i].Score > results[j].Score
+	})
+	if topK > 0 && len(results) > topK {
+		results = results[:topK]
+	}
+	return results
+}
+
+// Score returns the BM25 score of a single document against query, or 0 if the
+// document isn't indexed or shares no terms with the query.
+func (idx *Index) Score(docID, query string) float64 {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	return idx.scoreAll(Tokenize(query))[docID]
+}
+
+func (idx *Index) scoreAll(terms []string) map[string]float64 {
+	scores := make(map[string]float64)
+	if len(terms) == 0 || len(idx.docLen) == 0 {
+		return scores
+	}
+
+	n := float64(len(idx.docLen))
+	avgdl := idx.avgDocLen()
+
+	for _, term := range terms {
+		postings := idx.postings[term]
+		df := len(postings)
+		if df == 0 {
+			continue
+		}
+		termIDF := idf(n, float64(df))
+		for docID, tf := range postings {
+			dl := float64(idx.docLen[docID])
+			denom := float64(tf) + idx.K1*(1-idx.B+idx.B*dl/avgdl)
+			scores[docID] += termIDF * (float64(tf) * (idx.K1 + 1)) / denom
+		}
+	}
+	return scores
+}
@@ -0,0 +1,75 @@
+package search
+
+import "testing"
+
+func TestTokenizeChinese(t *testing.T) {
+	got := Tokenize("网络安全")
+	want := []string{"网", "络", "安", "全", "网络", "络安", "安全"}
+	if !equalTokens(got, want) {
+		t.Fatalf("Tokenize(网络安全) = %v, want %v", got, want)
+	}
+}
+
+func TestTokenizeMixed(t *testing.T) {
+	got := Tokenize("TCP/IP 协议")
+	want := []string{"tcp/ip", "协", "议", "协议"}
+	if !equalTokens(got, want) {
+		t.Fatalf("Tokenize(TCP/IP 协议) = %v, want %v", got, want)
+	}
+}
+
+func equalTokens(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSearchPureChineseQuery(t *testing.T) {
+	idx := NewIndex()
+	idx.Add("a", "网络安全的基本概念")
+	idx.Add("b", "计算机网络的发展历史")
+	idx.Add("c", "操作系统进程调度算法")
+
+	results := idx.Search("网络安全", 10)
+	if len(results) == 0 || results[0].UUID != "a" {
+		t.Fatalf("Search(网络安全) = %v, want doc a ranked first", results)
+	}
+}
+
+func TestSearchMixedQuery(t *testing.T) {
+	idx := NewIndex()
+	idx.Add("a", "TCP协议的三次握手")
+	idx.Add("b", "UDP是无连接协议")
+	idx.Add("c", "进程与线程的区别")
+
+	results := idx.Search("TCP 协议", 10)
+	if len(results) == 0 || results[0].UUID != "a" {
+		t.Fatalf("Search(TCP 协议) = %v, want doc a ranked first", results)
+	}
+}
+
+func TestSearchDuplicateTermBoosting(t *testing.T) {
+	idx := NewIndex()
+	idx.Add("a", "安全 安全 安全")
+	idx.Add("b", "安全")
+
+	once := idx.Score("b", "安全")
+	repeated := idx.Score("b", "安全 安全")
+
+	if repeated <= once {
+		t.Fatalf("repeating the query term should increase the score: repeated=%v once=%v", repeated, once)
+	}
+}
+
+func TestSearchEmptyIndex(t *testing.T) {
+	idx := NewIndex()
+	if results := idx.Search("网络", 10); len(results) != 0 {
+		t.Fatalf("Search on empty index = %v, want empty", results)
+	}
+}
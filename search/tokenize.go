@@ -0,0 +1,58 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// isSplitter reports whether r separates ASCII tokens. '/' is excluded even
+// though unicode.IsPunct considers it punctuation, so slash-joined terms like
+// "TCP/IP" stay one token instead of splitting into "tcp" and "ip".
+func isSplitter(r rune) bool {
+	return unicode.IsSpace(r) || (unicode.IsPunct(r) && r != '/')
+}
+
+// Tokenize splits text into index terms. ASCII/Latin runs are split on whitespace
+// and punctuation (except '/', kept so terms like "TCP/IP" survive intact) and
+// lowercased, as the old keyword matching was. Han runs are emitted as both
+// unigrams and bigrams, since Chinese has no whitespace between words: "网络安全"
+// yields 网, 络, 安, 全, 网络, 络安, 安全.
+func Tokenize(text string) []string {
+	var tokens []string
+	var han []rune
+	var ascii []rune
+
+	flushHan := func() {
+		for _, r := range han {
+			tokens = append(tokens, string(r))
+		}
+		for i := 0; i+1 < len(han); i++ {
+			tokens = append(tokens, string(han[i:i+2]))
+		}
+		han = han[:0]
+	}
+	flushASCII := func() {
+		if len(ascii) > 0 {
+			tokens = append(tokens, strings.ToLower(string(ascii)))
+			ascii = ascii[:0]
+		}
+	}
+
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			flushASCII()
+			han = append(han, r)
+		case isSplitter(r):
+			flushHan()
+			flushASCII()
+		default:
+			flushHan()
+			ascii = append(ascii, r)
+		}
+	}
+	flushHan()
+	flushASCII()
+
+	return tokens
+}
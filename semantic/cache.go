@@ -0,0 +1,130 @@
+package semantic
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sync"
+)
+
+// cacheEntry is one cached score, in the JSON persisted to disk.
+type cacheEntry struct {
+	Key   string  `json:"key"`
+	Value float64 `json:"value"`
+}
+
+// Cache is an on-disk LRU of query|doc -> score, so a CachingRanker only calls
+// its wrapped Ranker once per distinct pair.
+type Cache struct {
+	mu       sync.Mutex
+	path     string
+	capacity int
+	order    *list.List // most-recently-used at the front
+	items    map[string]*list.Element
+}
+
+// NewCache opens (or creates) an LRU cache persisted to path, holding at most
+// capacity entries.
+func NewCache(path string, capacity int) (*Cache, error) {
+	c := &Cache{
+		path:     path,
+		capacity: capacity,
+		order:    list.New(),
+		items:    make(map[string]*list.Element),
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return c, nil
+		}
+		return nil, err
+	}
+
+	var entries []cacheEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	// entries is stored MRU-first; push back-to-front to restore that order.
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		c.items[e.Key] = c.order.PushFront(&cacheEntry{Key: e.Key, Value: e.Value})
+	}
+	return c, nil
+}
+
+// CacheKey hashes a (query, doc) pair with SHA-256 to use as the Cache's key.
+func CacheKey(query, doc string) string {
+	sum := sha256.Sum256([]byte(query + "|" + doc))
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *Cache) Get(key string) (float64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return 0, false
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*cacheEntry).Value, true
+}
+
+func (c *Cache) Set(key string, value float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.setLocked(key, value)
+	c.persistLocked()
+}
+
+// SetMany inserts every entry and persists to disk exactly once, instead of once
+// per entry, for callers (such as CachingRanker) filling in a whole batch of
+// cache misses from a single Ranker call.
+func (c *Cache) SetMany(entries map[string]float64) {
+	if len(entries) == 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, value := range entries {
+		c.setLocked(key, value)
+	}
+	c.persistLocked()
+}
+
+// setLocked inserts or updates one entry; callers must hold c.mu and persist
+// afterwards.
+func (c *Cache) setLocked(key string, value float64) {
+	if el, ok := c.items[key]; ok {
+		el.Value.(*cacheEntry).Value = value
+		c.order.MoveToFront(el)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&cacheEntry{Key: key, Value: value})
+	if c.capacity > 0 && c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*cacheEntry).Key)
+	}
+}
+
+// persistLocked writes the cache to disk; callers must hold c.mu.
+func (c *Cache) persistLocked() error {
+	entries := make([]cacheEntry, 0, c.order.Len())
+	for el := c.order.Front(); el != nil; el = el.Next() {
+		entries = append(entries, *el.Value.(*cacheEntry))
+	}
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(c.path, data, 0644)
+}
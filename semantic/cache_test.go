@@ -0,0 +1,81 @@
+package semantic
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestCacheGetSetRoundTrip(t *testing.T) {
+	c, err := NewCache(filepath.Join(t.TempDir(), "cache.json"), 10)
+	if err != nil {
+		t.Fatalf("NewCache() error: %v", err)
+	}
+
+	key := CacheKey("q", "d")
+	if _, ok := c.Get(key); ok {
+		t.Fatalf("Get() on empty cache should miss")
+	}
+
+	c.Set(key, 0.42)
+	if v, ok := c.Get(key); !ok || v != 0.42 {
+		t.Fatalf("Get() = %v, %v, want 0.42, true", v, ok)
+	}
+}
+
+func TestCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c, err := NewCache(filepath.Join(t.TempDir(), "cache.json"), 2)
+	if err != nil {
+		t.Fatalf("NewCache() error: %v", err)
+	}
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+	// touch "a" so "b" becomes the least recently used entry.
+	if _, ok := c.Get("a"); !ok {
+		t.Fatalf("Get(a) should hit before eviction")
+	}
+	c.Set("c", 3)
+
+	if _, ok := c.Get("b"); ok {
+		t.Fatalf("Get(b) should have been evicted once capacity was exceeded")
+	}
+	if _, ok := c.Get("a"); !ok {
+		t.Fatalf("Get(a) should survive eviction since it was most recently used")
+	}
+	if _, ok := c.Get("c"); !ok {
+		t.Fatalf("Get(c) should be present as the newest entry")
+	}
+}
+
+func TestCachePersistsAcrossReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+
+	c1, err := NewCache(path, 10)
+	if err != nil {
+		t.Fatalf("NewCache() error: %v", err)
+	}
+	c1.Set("k", 0.9)
+
+	c2, err := NewCache(path, 10)
+	if err != nil {
+		t.Fatalf("NewCache() reopen error: %v", err)
+	}
+	if v, ok := c2.Get("k"); !ok || v != 0.9 {
+		t.Fatalf("Get(k) after reopen = %v, %v, want 0.9, true", v, ok)
+	}
+}
+
+func TestCacheSetManyStoresAllEntries(t *testing.T) {
+	c, err := NewCache(filepath.Join(t.TempDir(), "cache.json"), 10)
+	if err != nil {
+		t.Fatalf("NewCache() error: %v", err)
+	}
+
+	c.SetMany(map[string]float64{"a": 1, "b": 2})
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Fatalf("Get(a) = %v, %v, want 1, true", v, ok)
+	}
+	if v, ok := c.Get("b"); !ok || v != 2 {
+		t.Fatalf("Get(b) = %v, %v, want 2, true", v, ok)
+	}
+}
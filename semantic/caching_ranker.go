@@ -0,0 +1,37 @@
+package semantic
+
+// CachingRanker wraps another Ranker and serves repeat (query, doc) pairs from
+// Cache instead of re-calling it, bounding how often an expensive (e.g. remote)
+// Ranker gets hit.
+type CachingRanker struct {
+	Inner Ranker
+	Cache *Cache
+}
+
+func (c *CachingRanker) Score(query string, docs []string) []float64 {
+	scores := make([]float64, len(docs))
+	var missDocs []string
+	var missIdx []int
+
+	for i, doc := range docs {
+		if v, ok := c.Cache.Get(CacheKey(query, doc)); ok {
+			scores[i] = v
+			continue
+		}
+		missDocs = append(missDocs, doc)
+		missIdx = append(missIdx, i)
+	}
+
+	if len(missDocs) == 0 {
+		return scores
+	}
+
+	fresh := c.Inner.Score(query, missDocs)
+	newEntries := make(map[string]float64, len(missIdx))
+	for j, idx := range missIdx {
+		scores[idx] = fresh[j]
+		newEntries[CacheKey(query, missDocs[j])] = fresh[j]
+	}
+	c.Cache.SetMany(newEntries)
+	return scores
+}
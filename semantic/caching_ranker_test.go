@@ -0,0 +1,70 @@
+package semantic
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// countingRanker returns a fixed score per call and records which docs it was
+// asked to score, so tests can assert CachingRanker only calls through on misses.
+type countingRanker struct {
+	score float64
+	calls [][]string
+}
+
+func (r *countingRanker) Score(query string, docs []string) []float64 {
+	r.calls = append(r.calls, append([]string(nil), docs...))
+	scores := make([]float64, len(docs))
+	for i := range scores {
+		scores[i] = r.score
+	}
+	return scores
+}
+
+func TestCachingRankerServesHitsWithoutCallingInner(t *testing.T) {
+	cache, err := NewCache(filepath.Join(t.TempDir(), "cache.json"), 10)
+	if err != nil {
+		t.Fatalf("NewCache() error: %v", err)
+	}
+	cache.Set(CacheKey("q", "d1"), 0.75)
+
+	inner := &countingRanker{score: 1}
+	r := &CachingRanker{Inner: inner, Cache: cache}
+
+	scores := r.Score("q", []string{"d1"})
+	if len(scores) != 1 || scores[0] != 0.75 {
+		t.Fatalf("Score() = %v, want cached 0.75", scores)
+	}
+	if len(inner.calls) != 0 {
+		t.Fatalf("inner ranker was called on an all-hit batch: %v", inner.calls)
+	}
+}
+
+func TestCachingRankerMergesHitsAndMisses(t *testing.T) {
+	cache, err := NewCache(filepath.Join(t.TempDir(), "cache.json"), 10)
+	if err != nil {
+		t.Fatalf("NewCache() error: %v", err)
+	}
+	cache.Set(CacheKey("q", "cached"), 0.9)
+
+	inner := &countingRanker{score: 0.2}
+	r := &CachingRanker{Inner: inner, Cache: cache}
+
+	scores := r.Score("q", []string{"cached", "miss"})
+	if len(scores) != 2 || scores[0] != 0.9 || scores[1] != 0.2 {
+		t.Fatalf("Score() = %v, want [0.9, 0.2]", scores)
+	}
+	if len(inner.calls) != 1 || len(inner.calls[0]) != 1 || inner.calls[0][0] != "miss" {
+		t.Fatalf("inner ranker calls = %v, want exactly one call for the miss doc", inner.calls)
+	}
+
+	// the miss should now be cached, so a second call shouldn't hit the inner ranker again.
+	inner.calls = nil
+	scores = r.Score("q", []string{"cached", "miss"})
+	if len(scores) != 2 || scores[0] != 0.9 || scores[1] != 0.2 {
+		t.Fatalf("Score() after caching miss = %v, want [0.9, 0.2]", scores)
+	}
+	if len(inner.calls) != 0 {
+		t.Fatalf("inner ranker was called again after its result was cached: %v", inner.calls)
+	}
+}
@@ -0,0 +1,78 @@
+package semantic
+
+import (
+	"hash/fnv"
+	"math"
+)
+
+// LocalRanker scores similarity with character n-gram hashing + cosine similarity,
+// so it needs no external model or network call and works the same for Chinese
+// and Latin text.
+type LocalRanker struct {
+	NGram int // n-gram size, in runes
+	Dims  int // hashed vector width
+}
+
+// NewLocalRanker returns a LocalRanker with sane defaults: 3-rune grams hashed
+// into a 4096-wide vector.
+func NewLocalRanker() *LocalRanker {
+	return &LocalRanker{NGram: 3, Dims: 4096}
+}
+
+func (l *LocalRanker) Score(query string, docs []string) []float64 {
+	qv := l.vector(query)
+	scores := make([]float64, len(docs))
+	for i, doc := range docs {
+		scores[i] = cosine(qv, l.vector(doc))
+	}
+	return scores
+}
+
+// vector hashes every n-gram of text into a sparse, hashed bag-of-ngrams vector.
+func (l *LocalRanker) vector(text string) map[uint32]float64 {
+	n := l.NGram
+	if n <= 0 {
+		n = 3
+	}
+	dims := l.Dims
+	if dims <= 0 {
+		dims = 4096
+	}
+
+	runes := []rune(text)
+	v := make(map[uint32]float64)
+	if len(runes) == 0 {
+		return v
+	}
+	if len(runes) < n {
+		v[hashNGram(string(runes))%uint32(dims)]++
+		return v
+	}
+	for i := 0; i+n <= len(runes); i++ {
+		v[hashNGram(string(runes[i:i+n]))%uint32(dims)]++
+	}
+	return v
+}
+
+func hashNGram(gram string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(gram))
+	return h.Sum32()
+}
+
+func cosine(a, b map[uint32]float64) float64 {
+	var dot, normA, normB float64
+	for dim, va := range a {
+		normA += va * va
+		if vb, ok := b[dim]; ok {
+			dot += va * vb
+		}
+	}
+	for _, vb := range b {
+		normB += vb * vb
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
@@ -0,0 +1,15 @@
+// Package semantic provides an optional relevance backend that scores a query
+// against a document by topical similarity rather than shared terms, so a query
+// phrased differently from a question can still rank it highly.
+package semantic
+
+// Ranker scores query against each of docs, returning one score per doc in the
+// same order. A score is not required to be bounded to [0, 1]; callers that
+// blend it with another signal decide how to weight it.
+type Ranker interface {
+	Score(query string, docs []string) []float64
+}
+
+func zeroScores(n int) []float64 {
+	return make([]float64, n)
+}
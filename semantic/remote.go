@@ -0,0 +1,104 @@
+package semantic
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+// RemoteRanker posts to a configurable NLP text-similarity endpoint. The field
+// names of the request/response JSON are configurable too, so no vendor is
+// hardcoded: any endpoint that takes "a query, a list of docs" and returns "a
+// list of scores" can be wired in through env vars.
+type RemoteRanker struct {
+	Client      *http.Client
+	URL         string
+	APIKey      string
+	QueryField  string // request field holding the query string
+	DocsField   string // request field holding the []string of docs
+	ScoresField string // response field holding the []float64 of scores
+}
+
+// NewRemoteRankerFromEnv builds a RemoteRanker from SEMANTIC_API_* env vars. It
+// returns ok=false if SEMANTIC_API_URL isn't set, meaning no remote backend is
+// configured.
+func NewRemoteRankerFromEnv() (r *RemoteRanker, ok bool) {
+	url := os.Getenv("SEMANTIC_API_URL")
+	if url == "" {
+		return nil, false
+	}
+
+	timeout := 3 * time.Second
+	if raw := os.Getenv("SEMANTIC_API_TIMEOUT_MS"); raw != "" {
+		if ms, err := strconv.Atoi(raw); err == nil && ms > 0 {
+			timeout = time.Duration(ms) * time.Millisecond
+		}
+	}
+
+	return &RemoteRanker{
+		Client:      &http.Client{Timeout: timeout},
+		URL:         url,
+		APIKey:      os.Getenv("SEMANTIC_API_KEY"),
+		QueryField:  envOr("SEMANTIC_API_QUERY_FIELD", "query"),
+		DocsField:   envOr("SEMANTIC_API_DOCS_FIELD", "docs"),
+		ScoresField: envOr("SEMANTIC_API_SCORES_FIELD", "scores"),
+	}, true
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// Score posts query and docs to URL and parses the scores back out. Any failure
+// (network error, timeout, bad status, schema mismatch) degrades to all-zero
+// scores rather than erroring, so callers fall back to lexical-only ranking.
+func (r *RemoteRanker) Score(query string, docs []string) []float64 {
+	payload, err := json.Marshal(map[string]interface{}{
+		r.QueryField: query,
+		r.DocsField:  docs,
+	})
+	if err != nil {
+		return zeroScores(len(docs))
+	}
+
+	req, err := http.NewRequest(http.MethodPost, r.URL, bytes.NewReader(payload))
+	if err != nil {
+		return zeroScores(len(docs))
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if r.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+r.APIKey)
+	}
+
+	resp, err := r.Client.Do(req)
+	if err != nil {
+		return zeroScores(len(docs))
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return zeroScores(len(docs))
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return zeroScores(len(docs))
+	}
+
+	var envelope map[string]json.RawMessage
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return zeroScores(len(docs))
+	}
+
+	var scores []float64
+	if err := json.Unmarshal(envelope[r.ScoresField], &scores); err != nil || len(scores) != len(docs) {
+		return zeroScores(len(docs))
+	}
+	return scores
+}
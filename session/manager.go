@@ -0,0 +1,64 @@
+package session
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// CookieName is the cookie the Manager uses to track which Session a request
+// belongs to.
+const CookieName = "exam_session"
+
+// Manager mints and looks up Sessions, storing them in a Store.
+type Manager struct {
+	Store Store
+}
+
+// NewManager returns a Manager backed by store.
+func NewManager(store Store) *Manager {
+	return &Manager{Store: store}
+}
+
+// SessionID returns the id carried by the request's cookie, if it names a
+// Session the Store actually minted; otherwise it mints a new 32-character id
+// and sets it as a cookie on the response, the same pattern an external
+// session manager would use. A cookie value is never trusted on shape alone
+// (length 32), since that would let a client pick its own session id and hand
+// out a guessable/shared exam result to anyone who later presents it.
+func (m *Manager) SessionID(w http.ResponseWriter, r *http.Request) string {
+	if c, err := r.Cookie(CookieName); err == nil && len(c.Value) == 32 {
+		if _, err := m.Store.Get(c.Value); err == nil {
+			return c.Value
+		}
+	}
+
+	id := newSessionID()
+	http.SetCookie(w, &http.Cookie{
+		Name:     CookieName,
+		Value:    id,
+		Path:     "/",
+		HttpOnly: true,
+	})
+	return id
+}
+
+// Start creates a new Session for the request's session id over questions and
+// persists it.
+func (m *Manager) Start(w http.ResponseWriter, r *http.Request, questions []Question, maxDuration int) (*Session, error) {
+	id := m.SessionID(w, r)
+	sess := NewSession(id, questions, maxDuration)
+	if err := m.Store.Create(sess); err != nil {
+		return nil, err
+	}
+	return sess, nil
+}
+
+// newSessionID returns a random 32-character hex id.
+func newSessionID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err) // crypto/rand is not expected to fail
+	}
+	return hex.EncodeToString(b)
+}
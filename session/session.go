@@ -0,0 +1,152 @@
+// Package session backs the "take exam" HTTP routes: it tracks which questions a
+// user was given, their answers, and grades the attempt when submitted.
+package session
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Choice is the subset of a question's choice the grader needs.
+type Choice struct {
+	Operator  string
+	IsCorrect bool
+}
+
+// Question is the subset of a question an exam attempt needs, independent of how
+// the caller stores its full question bank.
+type Question struct {
+	UUID    string
+	Type    string
+	Title   string
+	Score   float64
+	Choices []Choice
+}
+
+// Result is the graded outcome for one question in a submitted Session.
+type Result struct {
+	QuestionUUID string
+	Type         string
+	Selected     []string
+	CorrectOps   []string
+	Correct      bool
+	Score        float64
+}
+
+// Session is one user's attempt at a fixed set of Questions.
+type Session struct {
+	ID          string
+	Questions   []Question
+	MaxDuration int // seconds; 0 means unlimited
+	StartedAt   time.Time
+	SubmittedAt time.Time
+	Results     []Result
+
+	mu      sync.Mutex
+	answers map[string][]string // question UUID -> selected operators
+}
+
+// NewSession creates a Session over questions, starting the clock now.
+func NewSession(id string, questions []Question, maxDuration int) *Session {
+	return &Session{
+		ID:          id,
+		Questions:   questions,
+		MaxDuration: maxDuration,
+		StartedAt:   time.Now(),
+		answers:     make(map[string][]string),
+	}
+}
+
+// Answer records the operators the user selected for questionUUID, overwriting
+// any previous answer to the same question.
+func (s *Session) Answer(questionUUID string, operators []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.answers[questionUUID] = operators
+}
+
+// SelectedFor returns the operators previously recorded for questionUUID.
+func (s *Session) SelectedFor(questionUUID string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.answers[questionUUID]
+}
+
+// Elapsed is how long the attempt has been running.
+func (s *Session) Elapsed() time.Duration {
+	return time.Since(s.StartedAt)
+}
+
+// Expired reports whether the attempt has run past ExamInfo.MaxDuration.
+func (s *Session) Expired() bool {
+	if s.MaxDuration <= 0 {
+		return false
+	}
+	return s.Elapsed() > time.Duration(s.MaxDuration)*time.Second
+}
+
+// Submit grades every question by comparing the recorded answer to each
+// question's correct operators, and is idempotent: resubmitting just re-grades
+// against whatever answers are recorded at the time.
+func (s *Session) Submit() []Result {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	results := make([]Result, 0, len(s.Questions))
+	for _, q := range s.Questions {
+		var correctOps []string
+		for _, c := range q.Choices {
+			if c.IsCorrect {
+				correctOps = append(correctOps, c.Operator)
+			}
+		}
+		selected := s.answers[q.UUID]
+		correct := sameOperators(selected, correctOps)
+		score := 0.0
+		if correct {
+			score = q.Score
+		}
+		results = append(results, Result{
+			QuestionUUID: q.UUID,
+			Type:         q.Type,
+			Selected:     selected,
+			CorrectOps:   correctOps,
+			Correct:      correct,
+			Score:        score,
+		})
+	}
+
+	s.SubmittedAt = time.Now()
+	s.Results = results
+	return results
+}
+
+// TotalScore sums Results[*].Score; call after Submit.
+func (s *Session) TotalScore() float64 {
+	var total float64
+	for _, r := range s.Results {
+		total += r.Score
+	}
+	return total
+}
+
+func sameOperators(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := make(map[string]int, len(b))
+	for _, op := range b {
+		seen[op]++
+	}
+	for _, op := range a {
+		if seen[op] == 0 {
+			return false
+		}
+		seen[op]--
+	}
+	return true
+}
+
+// ErrNotFound is returned by a Store when a session id is unknown.
+var ErrNotFound = fmt.Errorf("session: not found")
@@ -0,0 +1,88 @@
+package session
+
+import "testing"
+
+func TestSubmitGradesSingleChoice(t *testing.T) {
+	s := NewSession("s1", []Question{
+		{
+			UUID:  "q1",
+			Type:  "single_choice",
+			Score: 5,
+			Choices: []Choice{
+				{Operator: "A", IsCorrect: false},
+				{Operator: "B", IsCorrect: true},
+			},
+		},
+	}, 0)
+	s.Answer("q1", []string{"B"})
+
+	results := s.Submit()
+	if len(results) != 1 || !results[0].Correct || results[0].Score != 5 {
+		t.Fatalf("Submit() = %+v, want q1 correct with score 5", results)
+	}
+}
+
+func TestSubmitGradesMultiChoiceOrderIndependent(t *testing.T) {
+	s := NewSession("s1", []Question{
+		{
+			UUID:  "q1",
+			Type:  "choice",
+			Score: 10,
+			Choices: []Choice{
+				{Operator: "A", IsCorrect: true},
+				{Operator: "B", IsCorrect: false},
+				{Operator: "C", IsCorrect: true},
+			},
+		},
+	}, 0)
+	s.Answer("q1", []string{"C", "A"})
+
+	results := s.Submit()
+	if len(results) != 1 || !results[0].Correct {
+		t.Fatalf("Submit() = %+v, want q1 correct regardless of selection order", results)
+	}
+}
+
+func TestSubmitRejectsPartialOrExtraSelection(t *testing.T) {
+	correctChoices := []Choice{
+		{Operator: "A", IsCorrect: true},
+		{Operator: "B", IsCorrect: true},
+	}
+
+	partial := NewSession("s1", []Question{{UUID: "q1", Type: "choice", Score: 10, Choices: correctChoices}}, 0)
+	partial.Answer("q1", []string{"A"})
+	if results := partial.Submit(); results[0].Correct {
+		t.Fatalf("Submit() with a partial selection should not be graded correct: %+v", results)
+	}
+
+	extra := NewSession("s2", []Question{{UUID: "q1", Type: "choice", Score: 10, Choices: correctChoices}}, 0)
+	extra.Answer("q1", []string{"A", "B", "C"})
+	if results := extra.Submit(); results[0].Correct {
+		t.Fatalf("Submit() with an extra selection should not be graded correct: %+v", results)
+	}
+}
+
+func TestSubmitUnansweredQuestionIsIncorrect(t *testing.T) {
+	s := NewSession("s1", []Question{
+		{UUID: "q1", Type: "determine", Score: 2, Choices: []Choice{{Operator: "T", IsCorrect: true}}},
+	}, 0)
+
+	results := s.Submit()
+	if len(results) != 1 || results[0].Correct || results[0].Score != 0 {
+		t.Fatalf("Submit() with no answer = %+v, want q1 incorrect with score 0", results)
+	}
+}
+
+func TestTotalScoreSumsResults(t *testing.T) {
+	s := NewSession("s1", []Question{
+		{UUID: "q1", Type: "single_choice", Score: 5, Choices: []Choice{{Operator: "A", IsCorrect: true}}},
+		{UUID: "q2", Type: "single_choice", Score: 3, Choices: []Choice{{Operator: "A", IsCorrect: true}}},
+	}, 0)
+	s.Answer("q1", []string{"A"})
+	s.Answer("q2", []string{"B"})
+
+	s.Submit()
+	if got := s.TotalScore(); got != 5 {
+		t.Fatalf("TotalScore() = %v, want 5", got)
+	}
+}
@@ -0,0 +1,39 @@
+package session
+
+import "sync"
+
+// Store persists Sessions. MemoryStore is the only implementation today; the
+// interface exists so a Redis/SQLite-backed Store can be dropped in later
+// without touching the HTTP handlers.
+type Store interface {
+	Create(sess *Session) error
+	Get(id string) (*Session, error)
+}
+
+// MemoryStore keeps every Session in memory for the lifetime of the process.
+type MemoryStore struct {
+	mu       sync.RWMutex
+	sessions map[string]*Session
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{sessions: make(map[string]*Session)}
+}
+
+func (m *MemoryStore) Create(sess *Session) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sessions[sess.ID] = sess
+	return nil
+}
+
+func (m *MemoryStore) Get(id string) (*Session, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	sess, ok := m.sessions[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return sess, nil
+}